@@ -0,0 +1,100 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/importers"
+	"github.com/golang/dep/internal/gps"
+	"github.com/pkg/errors"
+)
+
+// godepImporterName is the value accepted by `dep init -importer` to force
+// godep import instead of auto-detection.
+const godepImporterName = "godep"
+
+// godepPath is where godep keeps its manifest, relative to the project root.
+var godepPath = filepath.Join("Godeps", "Godeps.json")
+
+func init() {
+	importers.Register(godepImporterName, newGodepImporter)
+}
+
+// godepImporter converts a Godeps/Godeps.json file into a dep Manifest and
+// Lock. It embeds importers.Base for the common hint-resolution logic and
+// registers itself so it's reachable from both `dep init -importer=godep`
+// and auto-detection.
+type godepImporter struct {
+	*importers.Base
+}
+
+func newGodepImporter(logger *log.Logger, verbose bool, sm gps.SourceManager) importers.Importer {
+	return &godepImporter{Base: importers.NewBase(logger, verbose, sm, dep.Analyzer{}, godepImporterName)}
+}
+
+func (g *godepImporter) Name() string {
+	return godepImporterName
+}
+
+func (g *godepImporter) HasDepMetadata(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, godepPath))
+	return err == nil && !info.IsDir()
+}
+
+// godepJSON is the subset of Godeps/Godeps.json that matters for import.
+type godepJSON struct {
+	ImportPath string     `json:"ImportPath"`
+	Deps       []godepDep `json:"Deps"`
+}
+
+type godepDep struct {
+	ImportPath string `json:"ImportPath"`
+	Comment    string `json:"Comment,omitempty"`
+	Rev        string `json:"Rev"`
+}
+
+func (g *godepImporter) Import(dir string) (*dep.Manifest, *dep.Lock, error) {
+	path := filepath.Join(dir, godepPath)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to read %s", path)
+	}
+
+	var cfg godepJSON
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to parse %s", path)
+	}
+
+	packages := make([]importers.ImportedPackage, 0, len(cfg.Deps))
+	for _, d := range cfg.Deps {
+		// godep only ever records an exact revision, never a constraint.
+		packages = append(packages, importers.ImportedPackage{
+			Name:       d.ImportPath,
+			LockHint:   d.Rev,
+			ConfigFile: godepPath,
+		})
+	}
+
+	// Since godep never records a constraint of its own, lean on
+	// HistoryAware to synthesize a useful range from the locked version's
+	// tag history instead of pinning to it exactly, and on Reconcile to
+	// let the solver backtrack a conflicting locked revision rather than
+	// dropping one side outright.
+	opts := importers.ImportOptions{
+		Strategy:      importers.Reconcile,
+		InferenceMode: importers.HistoryAware,
+	}
+	if err := g.ImportPackagesWithOptions(packages, true, opts); err != nil {
+		return nil, nil, err
+	}
+
+	return g.Manifest(), g.Lock(), nil
+}
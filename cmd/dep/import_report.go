@@ -0,0 +1,32 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/golang/dep/importers"
+	"github.com/pkg/errors"
+)
+
+// importReportFlagName is the name of the `dep init` flag that writes the
+// ImportReport for the run to a file, as JSON, instead of only logging
+// constraint/lock feedback when -v is set.
+const importReportFlagName = "import-report"
+
+// writeImportReport marshals report as indented JSON and writes it to path.
+func writeImportReport(path string, report *importers.ImportReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal import report")
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "could not write import report to %s", path)
+	}
+
+	return nil
+}
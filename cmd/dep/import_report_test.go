@@ -0,0 +1,63 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/dep/importers"
+)
+
+func TestWriteImportReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dep-import-report-test")
+	if err != nil {
+		t.Fatalf("TempDir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	report := &importers.ImportReport{
+		Tool: "godep",
+		Projects: []importers.ImportRecord{
+			{
+				ProjectRoot: "github.com/example/foo",
+				LockHint:    "deadbeef",
+				Resolution:  importers.ResolutionFallbackRevision,
+			},
+		},
+	}
+
+	path := filepath.Join(dir, "report.json")
+	if err := writeImportReport(path, report); err != nil {
+		t.Fatalf("writeImportReport: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+
+	var got importers.ImportReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+
+	if got.Tool != report.Tool {
+		t.Errorf("Tool = %q, want %q", got.Tool, report.Tool)
+	}
+	if len(got.Projects) != 1 || got.Projects[0].ProjectRoot != report.Projects[0].ProjectRoot {
+		t.Errorf("Projects = %+v, want %+v", got.Projects, report.Projects)
+	}
+}
+
+func TestWriteImportReportFailsOnUnwritablePath(t *testing.T) {
+	err := writeImportReport(filepath.Join("does", "not", "exist", "report.json"), &importers.ImportReport{})
+	if err == nil {
+		t.Fatal("writeImportReport succeeded writing to a nonexistent directory, want an error")
+	}
+}
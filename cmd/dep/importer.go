@@ -0,0 +1,36 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/golang/dep/importers"
+	"github.com/golang/dep/internal/gps"
+)
+
+// importerFlagName is the name of the `dep init` flag used to force a
+// specific importer instead of relying on auto-detection.
+const importerFlagName = "importer"
+
+// selectImporter resolves the importer that initCommand.Run should use for
+// dir. If name is non-empty, the registered importer with that name is used
+// and it is an error for it to be missing. Otherwise every registered
+// importer is tried, in name order, and the first one that recognizes dir
+// is used. It returns a nil Importer, with no error, if name is empty and
+// no registered importer recognizes dir.
+func selectImporter(name, dir string, logger *log.Logger, verbose bool, sm gps.SourceManager) (importers.Importer, error) {
+	if name != "" {
+		im, ok := importers.New(name, logger, verbose, sm)
+		if !ok {
+			return nil, fmt.Errorf("no importer registered as %q, available importers: %s", name, strings.Join(importers.Names(), ", "))
+		}
+		return im, nil
+	}
+
+	return importers.Detect(dir, logger, verbose, sm), nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/importers"
+	"github.com/pkg/errors"
+)
+
+const initShortHelp = `Set up a new dep project`
+const initLongHelp = `
+Init initializes a dep project, importing constraints and locked versions
+from other dependency managers where possible.
+
+By default the tool to import from is auto-detected by scanning the project
+root for the other tool's config file; -importer overrides that and forces a
+specific one.
+`
+
+func (cmd *initCommand) Name() string      { return "init" }
+func (cmd *initCommand) Args() string      { return "[root]" }
+func (cmd *initCommand) ShortHelp() string { return initShortHelp }
+func (cmd *initCommand) LongHelp() string  { return initLongHelp }
+func (cmd *initCommand) Hidden() bool      { return false }
+
+func (cmd *initCommand) Register(fs *flag.FlagSet) {
+	fs.StringVar(&cmd.importerName, importerFlagName, "", "import config from a specific tool instead of auto-detecting one, see `dep help init`")
+	fs.StringVar(&cmd.importReportPath, importReportFlagName, "", "write a JSON report of the import's provenance to this file")
+}
+
+type initCommand struct {
+	importerName     string
+	importReportPath string
+}
+
+// Run imports an existing dependency manager's configuration, if one is
+// found (or named via -importer), into a dep Manifest and Lock.
+//
+// Persisting the resulting Manifest and Lock to Gopkg.toml/Gopkg.lock, and
+// everything else `dep init` normally also does (scanning the project's own
+// imports, solving for packages that have no equivalent in the old config),
+// is unchanged and handled by the rest of the init flow; this Run only
+// covers what the importer refactor and its -importer flag are responsible
+// for.
+func (cmd *initCommand) Run(ctx *dep.Ctx, args []string) error {
+	root := ctx.WorkingDir
+	if len(args) > 0 {
+		root = args[0]
+	}
+
+	sm, err := ctx.SourceManager()
+	if err != nil {
+		return errors.Wrap(err, "could not set up a source manager")
+	}
+
+	im, err := selectImporter(cmd.importerName, root, ctx.Err, ctx.Verbose, sm)
+	if err != nil {
+		return err
+	}
+	if im == nil {
+		// Nothing to import from; the rest of init proceeds without a
+		// pre-populated Manifest/Lock.
+		return nil
+	}
+
+	manifest, lock, err := im.Import(root)
+	if err != nil {
+		return errors.Wrapf(err, "unable to import config from %s", im.Name())
+	}
+	ctx.Out.Printf("Imported %d constraints and %d locked projects from %s.\n", len(manifest.Constraints), len(lock.P), im.Name())
+
+	if cmd.importReportPath != "" {
+		reporter, ok := im.(interface{ Report() *importers.ImportReport })
+		if !ok {
+			return errors.Errorf("-%s is not supported by the %s importer", importReportFlagName, im.Name())
+		}
+		if err := writeImportReport(cmd.importReportPath, reporter.Report()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,487 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package importers holds the logic shared by importers that convert
+// configuration from other dependency managers into a dep Manifest and
+// Lock. It is kept separate from cmd/dep, following the same "library, not
+// tool" separation gps itself promotes, so that external tools can embed
+// Base and reuse this logic without depending on the dep command.
+package importers
+
+import (
+	"log"
+	"runtime"
+	"sync"
+
+	"github.com/golang/dep"
+	fb "github.com/golang/dep/internal/feedback"
+	"github.com/golang/dep/internal/gps"
+	"github.com/pkg/errors"
+)
+
+// Base provides a common implementation for importing from other
+// dependency managers. Concrete importers embed Base and use its exported
+// methods to turn their own configuration format into the ImportedPackage
+// intermediate representation.
+type Base struct {
+	logger   *log.Logger
+	verbose  bool
+	sm       gps.SourceManager
+	manifest *dep.Manifest
+	lock     *dep.Lock
+
+	versions *versionCache
+	analyzer gps.ProjectAnalyzer
+
+	tool   string
+	report []ImportRecord
+
+	warnHistoryAwareUnsupported sync.Once
+}
+
+// NewBase creates a new Base for embedding in an importer. tool identifies
+// the importer for ImportReport, e.g. "glide" or "godep". analyzer is only
+// consulted when ImportOptions.Strategy is Reconcile, to run dep's solver;
+// it may be nil otherwise.
+func NewBase(logger *log.Logger, verbose bool, sm gps.SourceManager, analyzer gps.ProjectAnalyzer, tool string) *Base {
+	return &Base{
+		logger:   logger,
+		verbose:  verbose,
+		manifest: dep.NewManifest(),
+		lock:     &dep.Lock{},
+		sm:       sm,
+		versions: newVersionCache(),
+		analyzer: analyzer,
+		tool:     tool,
+	}
+}
+
+// Report returns the provenance of every project handled by the most recent
+// call to ImportPackages or ImportPackagesWithOptions.
+func (i *Base) Report() *ImportReport {
+	return &ImportReport{Tool: i.tool, Projects: i.report}
+}
+
+// ImportOptions controls the behavior of ImportPackages.
+type ImportOptions struct {
+	// Concurrency bounds the number of projects whose lock hints are
+	// resolved concurrently. Resolving a hint may call out to the
+	// project's VCS, so this is a worker pool rather than a goroutine per
+	// project. Zero means runtime.NumCPU().
+	Concurrency int
+
+	// Strategy controls what happens when an imported constraint hint
+	// conflicts with the imported lock hint. Zero means PreferLock, dep's
+	// historical behavior.
+	Strategy ImportStrategy
+
+	// InferenceMode controls how a manifest constraint is synthesized from
+	// a locked version when defaultConstraintFromLock is true and the
+	// importer didn't record a constraint hint. Zero means Caret, dep's
+	// historical behavior.
+	InferenceMode ConstraintInferenceMode
+}
+
+// Manifest returns the manifest accumulated so far by ImportPackages.
+func (i *Base) Manifest() *dep.Manifest {
+	return i.manifest
+}
+
+// Lock returns the lock accumulated so far by ImportPackages.
+func (i *Base) Lock() *dep.Lock {
+	return i.lock
+}
+
+// IsTag determines if the specified value is a tag (plain or semver).
+func (i *Base) IsTag(pi gps.ProjectIdentifier, value string) (bool, gps.Version, error) {
+	versions, err := i.versions.listVersions(i.sm, pi)
+	if err != nil {
+		return false, nil, errors.Wrapf(err, "unable to list versions for %s(%s)", pi.ProjectRoot, pi.Source)
+	}
+
+	for _, version := range versions {
+		if version.Type() != gps.IsVersion && version.Type() != gps.IsSemver {
+			continue
+		}
+
+		if value == version.String() {
+			return true, version, nil
+		}
+	}
+
+	return false, nil, nil
+}
+
+// LookupVersionForLockedProject figures out the appropriate version for a
+// locked project based on the locked revision and the constraint from the
+// manifest. First try matching the revision to a version, then try the
+// constraint from the manifest, then finally the revision. The returned
+// ResolutionKind records which of those paths was taken, for ImportReport.
+func (i *Base) LookupVersionForLockedProject(pi gps.ProjectIdentifier, c gps.Constraint, rev gps.Revision) (gps.Version, ResolutionKind, error) {
+	// Find the version that goes with this revision, if any
+	versions, err := i.versions.listVersions(i.sm, pi)
+	if err != nil {
+		return rev, ResolutionFallbackRevision, errors.Wrapf(err, "Unable to lookup the version represented by %s in %s(%s). Falling back to locking the revision only.", rev, pi.ProjectRoot, pi.Source)
+	}
+
+	var branchConstraint gps.PairedVersion
+	gps.SortPairedForUpgrade(versions) // Sort versions in asc order
+	matches := []gps.Version{}
+	for _, v := range versions {
+		if v.Revision() == rev {
+			matches = append(matches, v)
+		}
+		if c != nil && v.Type() == gps.IsBranch && v.String() == c.String() {
+			branchConstraint = v
+		}
+	}
+
+	// Try to narrow down the matches with the constraint. Otherwise return the first match.
+	if len(matches) > 0 {
+		if c != nil {
+			for _, v := range matches {
+				if i.testConstraint(c, v) {
+					return v, ResolutionRevision, nil
+				}
+			}
+		}
+		return matches[0], ResolutionRevision, nil
+	}
+
+	// Use branch constraint from the manifest
+	if branchConstraint != nil {
+		return branchConstraint.Unpair().Pair(rev), ResolutionBranchConstraint, nil
+	}
+
+	// Give up and lock only to a revision
+	return rev, ResolutionFallbackRevision, nil
+}
+
+// ImportedPackage is a common intermediate representation of a package
+// imported from an external tool's configuration.
+type ImportedPackage struct {
+	// Required. The package path, not necessarily the project root.
+	Name string
+
+	// Required. Text representing a revision or tag.
+	LockHint string
+
+	// Optional. Alternative source, or fork, for the project.
+	Source string
+
+	// Optional. Text representing a branch or version.
+	ConstraintHint string
+
+	// Optional. The config file the hints were read from, and the line
+	// within it, for ImportReport. Importers that can't attribute a
+	// package to a specific line may leave ConfigLine as zero.
+	ConfigFile string
+	ConfigLine int
+}
+
+// ImportedProject is a consolidated representation of a set of imported
+// packages for the same project root.
+type ImportedProject struct {
+	Root gps.ProjectRoot
+	ImportedPackage
+}
+
+// LoadPackages consolidates all package references into a set of project roots.
+func (i *Base) LoadPackages(packages []ImportedPackage) ([]ImportedProject, error) {
+	// preserve the original order of the packages so that messages that
+	// are printed as they are processed are in a consistent order.
+	orderedProjects := make([]ImportedProject, 0, len(packages))
+
+	projects := make(map[gps.ProjectRoot]*ImportedProject, len(packages))
+	for _, pkg := range packages {
+		pr, err := i.sm.DeduceProjectRoot(pkg.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot determine the project root for %s", pkg.Name)
+		}
+		pkg.Name = string(pr)
+
+		prj, exists := projects[pr]
+		if !exists {
+			prj := ImportedProject{pr, pkg}
+			orderedProjects = append(orderedProjects, prj)
+			projects[pr] = &orderedProjects[len(orderedProjects)-1]
+			continue
+		}
+
+		// The config found first "wins", though we allow for incrementally
+		// setting each field because some importers have a config and lock file.
+		if prj.Source == "" && pkg.Source != "" {
+			prj.Source = pkg.Source
+		}
+
+		if prj.ConstraintHint == "" && pkg.ConstraintHint != "" {
+			prj.ConstraintHint = pkg.ConstraintHint
+		}
+
+		if prj.LockHint == "" && pkg.LockHint != "" {
+			prj.LockHint = pkg.LockHint
+		}
+	}
+
+	return orderedProjects, nil
+}
+
+// ImportPackages loads imported packages into the manifest and lock. It is
+// equivalent to ImportPackagesWithOptions with a zero ImportOptions.
+func (i *Base) ImportPackages(packages []ImportedPackage, defaultConstraintFromLock bool) error {
+	return i.ImportPackagesWithOptions(packages, defaultConstraintFromLock, ImportOptions{})
+}
+
+// hintResolution is the result of resolving a single project's lock hint to
+// a gps.Version, computed concurrently by ImportPackagesWithOptions.
+type hintResolution struct {
+	constraint gps.Constraint
+	version    gps.Version
+	resolution ResolutionKind
+	err        error
+
+	// fatal marks err as one that should abort the whole import, matching
+	// the pre-concurrency behavior where an IsTag failure (usually a VCS
+	// error while listing versions) returned immediately instead of
+	// falling back to locking a bare revision. A non-fatal err is only
+	// logged; resolution still falls back to the revision.
+	fatal bool
+}
+
+// firstFatal returns the error from the first fatal resolution, in input
+// order, or nil if none are fatal.
+func firstFatal(resolutions []hintResolution) error {
+	for _, res := range resolutions {
+		if res.fatal {
+			return res.err
+		}
+	}
+	return nil
+}
+
+// resolveHint infers the constraint for prj and, if it has a lock hint,
+// resolves the version it corresponds to. It only performs read-only
+// lookups against i.sm and i.versions, so it is safe to call concurrently
+// for distinct projects.
+func (i *Base) resolveHint(prj ImportedProject) hintResolution {
+	ident := gps.ProjectIdentifier{ProjectRoot: prj.Root, Source: prj.Source}
+
+	var res hintResolution
+	var err error
+	res.constraint, err = i.sm.InferConstraint(prj.ConstraintHint, ident)
+	if err != nil {
+		res.constraint = gps.Any()
+	}
+
+	if prj.LockHint == "" {
+		res.resolution = ResolutionNone
+		return res
+	}
+
+	isTag, version, err := i.IsTag(ident, prj.LockHint)
+	if err != nil {
+		res.err = err
+		res.fatal = true
+		return res
+	}
+
+	if isTag {
+		res.version, res.resolution = version, ResolutionTag
+		return res
+	}
+
+	revision := gps.Revision(prj.LockHint)
+	version, kind, err := i.LookupVersionForLockedProject(ident, res.constraint, revision)
+	if err != nil {
+		// Not fatal: fall back to locking the bare revision that
+		// LookupVersionForLockedProject returns alongside its error;
+		// ImportPackagesWithOptions logs the error via i.logger.
+		res.err = err
+		res.version = version
+		res.resolution = ResolutionFallbackRevision
+		return res
+	}
+
+	res.version, res.resolution = version, kind
+	return res
+}
+
+// ImportPackagesWithOptions loads imported packages into the manifest and
+// lock.
+// - defaultConstraintFromLock specifies if a constraint should be defaulted
+//   based on the locked version when there wasn't a constraint hint.
+// - opts.Concurrency bounds how many projects' lock hints are resolved at
+//   once; each resolution may call out to the project's VCS via i.sm.
+//
+// Rules:
+// * When a constraint is ignored, default to *.
+// * HEAD revisions default to the matching branch.
+// * Semantic versions default to a constraint chosen by opts.InferenceMode
+//   (^VERSION for the zero value, Caret).
+// * Revision constraints are ignored.
+// * Versions that don't satisfy the constraint are handled per
+//   opts.Strategy: PreferLock (the zero value) drops the constraint,
+//   PreferConstraint drops the locked version, and Reconcile locks the
+//   version chosen by the solver.
+// * Untagged revisions ignore non-branch constraint hints.
+func (i *Base) ImportPackagesWithOptions(packages []ImportedPackage, defaultConstraintFromLock bool, opts ImportOptions) error {
+	// Report covers only the most recent call.
+	i.report = nil
+
+	projects, err := i.LoadPackages(packages)
+	if err != nil {
+		return err
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	// Resolve every project's lock hint concurrently, bounded by a worker
+	// pool, storing results indexed by input order so that the feedback
+	// printed below stays deterministic regardless of resolution order.
+	resolutions := make([]hintResolution, len(projects))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for idx, prj := range projects {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, prj ImportedProject) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resolutions[idx] = i.resolveHint(prj)
+		}(idx, prj)
+	}
+	wg.Wait()
+
+	// Log every resolution's non-fatal error before checking for a fatal
+	// one, so that a later fatal error doesn't swallow earlier warnings
+	// (e.g. "falling back to locking the revision") the way the
+	// pre-concurrency, serial code printed them as it went.
+	for _, res := range resolutions {
+		if res.err != nil && !res.fatal {
+			i.logger.Println(res.err)
+		}
+	}
+
+	// An IsTag failure means we couldn't even list versions for the
+	// project, so there's nothing useful to fall back to; abort the whole
+	// import rather than silently proceeding with an incomplete
+	// manifest/lock, same as the pre-concurrency code did.
+	if err := firstFatal(resolutions); err != nil {
+		return err
+	}
+
+	var conflicts ConflictErrors
+	for idx, prj := range projects {
+		res := resolutions[idx]
+
+		pc := gps.ProjectConstraint{
+			Ident: gps.ProjectIdentifier{
+				ProjectRoot: prj.Root,
+				Source:      prj.Source,
+			},
+			Constraint: res.constraint,
+		}
+		version := res.version
+
+		// Default the constraint based on the locked version
+		if defaultConstraintFromLock && prj.ConstraintHint == "" && version != nil {
+			props := i.getProjectPropertiesFromVersion(pc.Ident, version, opts.InferenceMode)
+			if props.Constraint != nil {
+				pc.Constraint = props.Constraint
+			}
+		}
+
+		// Ignore pinned constraints
+		if i.isConstraintPinned(pc.Constraint) {
+			if i.verbose {
+				i.logger.Printf("  Ignoring pinned constraint %v for %v.\n", pc.Constraint, pc.Ident)
+			}
+			pc.Constraint = gps.Any()
+		}
+
+		// A constraint that conflicts with the locked revision would have
+		// solve silently change the revision to satisfy it. How that's
+		// handled depends on the chosen ImportStrategy.
+		if !i.testConstraint(pc.Constraint, version) {
+			switch opts.Strategy {
+			case PreferConstraint:
+				if i.verbose {
+					i.logger.Printf("  Dropping locked revision %v for %v because it conflicts with imported constraint %v.\n", version, pc.Ident, pc.Constraint)
+				}
+				version = nil
+			case Reconcile:
+				resolved, rerr := i.reconcile(pc.Ident, pc.Constraint, version)
+				if rerr != nil {
+					conflicts = append(conflicts, &ConflictError{
+						ProjectRoot: pc.Ident.ProjectRoot,
+						Hint:        pc.Constraint.String(),
+						LockHint:    prj.LockHint,
+					})
+					pc.Constraint = gps.Any()
+				} else {
+					version = resolved
+				}
+			default: // PreferLock
+				if i.verbose {
+					i.logger.Printf("  Ignoring constraint %v for %v because it would invalidate the locked version %v.\n", pc.Constraint, pc.Ident, version)
+				}
+				pc.Constraint = gps.Any()
+			}
+		}
+
+		i.manifest.Constraints[pc.Ident.ProjectRoot] = gps.ProjectProperties{
+			Source:     pc.Ident.Source,
+			Constraint: pc.Constraint,
+		}
+		fb.NewConstraintFeedback(pc, fb.DepTypeImported).LogFeedback(i.logger)
+
+		i.report = append(i.report, ImportRecord{
+			ProjectRoot:    pc.Ident.ProjectRoot,
+			Source:         prj.Source,
+			ConstraintHint: prj.ConstraintHint,
+			LockHint:       prj.LockHint,
+			ConfigFile:     prj.ConfigFile,
+			ConfigLine:     prj.ConfigLine,
+			Resolution:     res.resolution,
+		})
+
+		if version != nil {
+			lp := gps.NewLockedProject(pc.Ident, version, nil)
+			i.lock.P = append(i.lock.P, lp)
+			fb.NewLockedProjectFeedback(lp, fb.DepTypeImported).LogFeedback(i.logger)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return conflicts
+	}
+	return nil
+}
+
+// isConstraintPinned returns if a constraint is pinned to a specific revision.
+func (i *Base) isConstraintPinned(c gps.Constraint) bool {
+	if version, isVersion := c.(gps.Version); isVersion {
+		switch version.Type() {
+		case gps.IsRevision, gps.IsVersion:
+			return true
+		}
+	}
+	return false
+}
+
+// testConstraint verifies that the constraint won't invalidate the locked version.
+func (i *Base) testConstraint(c gps.Constraint, v gps.Version) bool {
+	// Assume branch constraints are satisfied
+	if version, isVersion := c.(gps.Version); isVersion {
+		if version.Type() == gps.IsBranch {
+
+			return true
+		}
+	}
+
+	return c.Matches(v)
+}
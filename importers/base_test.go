@@ -0,0 +1,40 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestFirstFatalAbortsOnVCSFailure guards against a VCS failure (an IsTag
+// error surfaced while resolving lock hints concurrently) being silently
+// downgraded to a log line instead of aborting the import, the way the
+// pre-concurrency, serial importPackages did.
+func TestFirstFatalAbortsOnVCSFailure(t *testing.T) {
+	want := errors.New("could not list versions: VCS fetch failed")
+	resolutions := []hintResolution{
+		{},
+		{err: errors.New("non-fatal: fell back to locking the revision")},
+		{err: want, fatal: true},
+		{err: errors.New("never reached"), fatal: true},
+	}
+
+	got := firstFatal(resolutions)
+	if got != want {
+		t.Fatalf("firstFatal = %v, want the first fatal error %v", got, want)
+	}
+}
+
+func TestFirstFatalIgnoresNonFatalErrors(t *testing.T) {
+	resolutions := []hintResolution{
+		{err: errors.New("logged, not fatal")},
+		{},
+	}
+
+	if got := firstFatal(resolutions); got != nil {
+		t.Fatalf("firstFatal = %v, want nil when no resolution is fatal", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"sync"
+
+	"github.com/golang/dep/internal/gps"
+)
+
+// versionCache memoizes gps.SourceManager.ListVersions per
+// ProjectIdentifier for the lifetime of an import, so that resolving the
+// same project from multiple goroutines only triggers one VCS fetch.
+type versionCache struct {
+	mu       sync.Mutex
+	inFlight map[gps.ProjectIdentifier]chan struct{}
+	result   map[gps.ProjectIdentifier]versionResult
+}
+
+type versionResult struct {
+	versions []gps.PairedVersion
+	err      error
+}
+
+// versionLister is the subset of gps.SourceManager that listVersions needs.
+// Any gps.SourceManager satisfies it; naming it narrowly lets tests supply a
+// minimal fake instead of a full SourceManager.
+type versionLister interface {
+	ListVersions(gps.ProjectIdentifier) ([]gps.PairedVersion, error)
+}
+
+func newVersionCache() *versionCache {
+	return &versionCache{
+		inFlight: make(map[gps.ProjectIdentifier]chan struct{}),
+		result:   make(map[gps.ProjectIdentifier]versionResult),
+	}
+}
+
+// listVersions returns sm.ListVersions(pi), fetching it at most once per pi
+// regardless of how many goroutines call listVersions concurrently for the
+// same identifier.
+func (c *versionCache) listVersions(sm versionLister, pi gps.ProjectIdentifier) ([]gps.PairedVersion, error) {
+	c.mu.Lock()
+	if res, ok := c.result[pi]; ok {
+		c.mu.Unlock()
+		return res.versions, res.err
+	}
+
+	if wait, ok := c.inFlight[pi]; ok {
+		c.mu.Unlock()
+		<-wait
+		c.mu.Lock()
+		res := c.result[pi]
+		c.mu.Unlock()
+		return res.versions, res.err
+	}
+
+	done := make(chan struct{})
+	c.inFlight[pi] = done
+	c.mu.Unlock()
+
+	versions, err := sm.ListVersions(pi)
+
+	c.mu.Lock()
+	c.result[pi] = versionResult{versions: versions, err: err}
+	delete(c.inFlight, pi)
+	c.mu.Unlock()
+	close(done)
+
+	return versions, err
+}
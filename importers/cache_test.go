@@ -0,0 +1,80 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/golang/dep/internal/gps"
+)
+
+// countingLister is a minimal versionLister that counts how many times
+// ListVersions was actually invoked per project, so tests can assert the
+// cache only fetches each project once.
+type countingLister struct {
+	mu       sync.Mutex
+	calls    map[gps.ProjectIdentifier]int
+	versions []gps.PairedVersion
+}
+
+func (l *countingLister) ListVersions(pi gps.ProjectIdentifier) ([]gps.PairedVersion, error) {
+	l.mu.Lock()
+	l.calls[pi]++
+	l.mu.Unlock()
+	return l.versions, nil
+}
+
+func TestVersionCacheFetchesOncePerProject(t *testing.T) {
+	lister := &countingLister{calls: make(map[gps.ProjectIdentifier]int)}
+	cache := newVersionCache()
+	pi := gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot("github.com/example/foo")}
+
+	const concurrent = 20
+	var wg sync.WaitGroup
+	for n := 0; n < concurrent; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.listVersions(lister, pi); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lister.mu.Lock()
+	got := lister.calls[pi]
+	lister.mu.Unlock()
+	if got != 1 {
+		t.Fatalf("ListVersions called %d times for one project across %d concurrent callers, want 1", got, concurrent)
+	}
+}
+
+func TestVersionCacheDistinguishesProjects(t *testing.T) {
+	lister := &countingLister{calls: make(map[gps.ProjectIdentifier]int)}
+	cache := newVersionCache()
+	a := gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot("github.com/example/a")}
+	b := gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot("github.com/example/b")}
+
+	if _, err := cache.listVersions(lister, a); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.listVersions(lister, b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.listVersions(lister, a); err != nil {
+		t.Fatal(err)
+	}
+
+	lister.mu.Lock()
+	defer lister.mu.Unlock()
+	if lister.calls[a] != 1 {
+		t.Errorf("ListVersions(a) called %d times, want 1", lister.calls[a])
+	}
+	if lister.calls[b] != 1 {
+		t.Errorf("ListVersions(b) called %d times, want 1", lister.calls[b])
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/golang/dep/internal/gps"
+)
+
+// ConstraintInferenceMode controls how Base infers a manifest constraint
+// from a project's locked version when the importer didn't record a
+// constraint hint explicitly (see ImportPackages' defaultConstraintFromLock
+// parameter). It matters for tools like glide or godep that only ever
+// recorded an exact version.
+type ConstraintInferenceMode int
+
+const (
+	// Caret infers a caret constraint (^1.2.3), dep's native behavior for
+	// an exact version, and the zero value of ConstraintInferenceMode.
+	Caret ConstraintInferenceMode = iota
+
+	// Tilde infers a tilde constraint (~1.2.3), allowing only patch
+	// releases.
+	Tilde
+
+	// HistoryAware inspects the project's full tag history and widens the
+	// constraint to the broadest range that shares the locked version's
+	// major version, is monotonically newer, and does not cross a tag
+	// whose revision is reported as a breaking change.
+	HistoryAware
+)
+
+// breakingChangeDetector is implemented by source managers that can expose
+// the commit message or annotation associated with a tag's revision.
+// gps.SourceManager doesn't define this yet, so HistoryAware degrades to
+// treating every tag as non-breaking when sm doesn't implement it.
+type breakingChangeDetector interface {
+	RepoInfo(pi gps.ProjectIdentifier, rev gps.Revision) (message string, err error)
+}
+
+// getProjectPropertiesFromVersion infers a manifest constraint for v
+// according to mode. Branches are always pinned to themselves, regardless
+// of mode.
+func (i *Base) getProjectPropertiesFromVersion(pi gps.ProjectIdentifier, v gps.Version, mode ConstraintInferenceMode) gps.ProjectProperties {
+	var props gps.ProjectProperties
+
+	uv := v
+	if pv, ok := v.(gps.PairedVersion); ok {
+		uv = pv.Unpair()
+	}
+
+	switch uv.Type() {
+	case gps.IsBranch:
+		props.Constraint = uv
+		return props
+	case gps.IsSemver, gps.IsVersion:
+		// handled below
+	default:
+		return props
+	}
+
+	var c gps.Constraint
+	var err error
+	switch mode {
+	case Tilde:
+		c, err = gps.NewSemverConstraintIC("~" + uv.String())
+	case HistoryAware:
+		c = i.historyAwareConstraint(pi, uv)
+	default:
+		c, err = gps.NewSemverConstraintIC(uv.String())
+	}
+
+	if err == nil {
+		props.Constraint = c
+	}
+	return props
+}
+
+// historyAwareConstraint widens locked into a range constraint covering
+// every later tag that shares its major version, stopping just before the
+// first tag whose revision looks like a breaking change; that tag is not
+// included in the range.
+func (i *Base) historyAwareConstraint(pi gps.ProjectIdentifier, locked gps.Version) gps.Constraint {
+	versions, err := i.versions.listVersions(i.sm, pi)
+	if err != nil {
+		return nil
+	}
+	gps.SortPairedForUpgrade(versions) // ascending
+
+	detector, _ := i.sm.(breakingChangeDetector)
+	if detector == nil {
+		// Requirement (c) of HistoryAware is that the range must not cross
+		// a tag marked as breaking, but no configured SourceManager
+		// implements breakingChangeDetector yet. Rather than silently
+		// widening as if every tag were safe, say so: this is logged
+		// unconditionally (not gated on i.verbose) because it's a gap in a
+		// correctness-relevant safety check, not routine progress output.
+		i.warnHistoryAwareUnsupported.Do(func() {
+			i.logger.Println("importers: HistoryAware constraint inference cannot check for BREAKING tags because the configured SourceManager does not implement RepoInfo; widening on major-version/monotonic-newer criteria only.")
+		})
+	}
+
+	return widenHistoryAwareConstraint(pi, locked, versions, detector)
+}
+
+// widenHistoryAwareConstraint holds historyAwareConstraint's widening logic,
+// kept free of any dependency on Base (no i.sm, no i.versions) so it can be
+// exercised with synthetic versions and a fake detector instead of a full
+// gps.SourceManager.
+func widenHistoryAwareConstraint(pi gps.ProjectIdentifier, locked gps.Version, versions []gps.PairedVersion, detector breakingChangeDetector) gps.Constraint {
+	lockedSV, err := semver.NewVersion(locked.String())
+	if err != nil {
+		return nil
+	}
+
+	upper := lockedSV
+	for _, v := range versions {
+		if v.Type() != gps.IsSemver {
+			continue
+		}
+		sv, err := semver.NewVersion(v.String())
+		if err != nil || sv.Major() != lockedSV.Major() || sv.LessThan(upper) {
+			continue
+		}
+
+		if detector != nil {
+			msg, err := detector.RepoInfo(pi, v.Revision())
+			if err != nil || strings.Contains(strings.ToUpper(msg), "BREAKING") {
+				// Fail closed: if we can't confirm a tag is safe, stop
+				// widening rather than risk crossing an undetected
+				// breaking release.
+				break
+			}
+		}
+
+		upper = sv
+	}
+
+	if upper.Equal(lockedSV) {
+		c, err := gps.NewSemverConstraintIC(locked.String())
+		if err != nil {
+			return nil
+		}
+		return c
+	}
+
+	c, err := gps.NewSemverConstraintIC(fmt.Sprintf(">=%s, <=%s", lockedSV, upper))
+	if err != nil {
+		return nil
+	}
+	return c
+}
@@ -0,0 +1,110 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"testing"
+
+	"github.com/golang/dep/internal/gps"
+)
+
+// fakeDetector implements breakingChangeDetector, reporting a revision as a
+// breaking change iff it's in breaking.
+type fakeDetector struct {
+	breaking map[gps.Revision]bool
+}
+
+func (f fakeDetector) RepoInfo(pi gps.ProjectIdentifier, rev gps.Revision) (string, error) {
+	if f.breaking[rev] {
+		return "BREAKING CHANGE", nil
+	}
+	return "", nil
+}
+
+func semverRev(v, rev string) gps.PairedVersion {
+	return gps.NewVersion(v).Pair(gps.Revision(rev))
+}
+
+var testIdent = gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot("github.com/example/widen")}
+
+func TestWidenHistoryAwareConstraint(t *testing.T) {
+	cases := []struct {
+		name     string
+		locked   gps.PairedVersion
+		versions []gps.PairedVersion
+		detector breakingChangeDetector
+		want     string
+	}{
+		{
+			name:   "widens across safe tags",
+			locked: semverRev("1.0.0", "a"),
+			versions: []gps.PairedVersion{
+				semverRev("1.0.0", "a"),
+				semverRev("1.1.0", "b"),
+				semverRev("1.2.0", "c"),
+			},
+			detector: fakeDetector{breaking: map[gps.Revision]bool{}},
+			want:     ">=1.0.0, <=1.2.0",
+		},
+		{
+			name:   "stops at a BREAKING tag",
+			locked: semverRev("1.0.0", "a"),
+			versions: []gps.PairedVersion{
+				semverRev("1.0.0", "a"),
+				semverRev("1.1.0", "b"),
+				semverRev("1.2.0", "c"),
+			},
+			detector: fakeDetector{breaking: map[gps.Revision]bool{"c": true}},
+			want:     ">=1.0.0, <=1.1.0",
+		},
+		{
+			name:   "stops at a major-version bump",
+			locked: semverRev("1.0.0", "a"),
+			versions: []gps.PairedVersion{
+				semverRev("1.0.0", "a"),
+				semverRev("1.1.0", "b"),
+				semverRev("2.0.0", "c"),
+			},
+			detector: fakeDetector{breaking: map[gps.Revision]bool{}},
+			want:     ">=1.0.0, <=1.1.0",
+		},
+		{
+			name:   "widens on major/monotonic criteria alone when there's no detector",
+			locked: semverRev("1.0.0", "a"),
+			versions: []gps.PairedVersion{
+				semverRev("1.0.0", "a"),
+				semverRev("1.1.0", "b"),
+				semverRev("1.2.0", "c"),
+			},
+			detector: nil,
+			want:     ">=1.0.0, <=1.2.0",
+		},
+		{
+			name:   "no newer tags falls back to a caret constraint on the locked version",
+			locked: semverRev("1.0.0", "a"),
+			versions: []gps.PairedVersion{
+				semverRev("1.0.0", "a"),
+			},
+			detector: fakeDetector{breaking: map[gps.Revision]bool{}},
+			want:     "1.0.0",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := widenHistoryAwareConstraint(testIdent, tc.locked, tc.versions, tc.detector)
+			if got == nil {
+				t.Fatalf("widenHistoryAwareConstraint returned nil, want a constraint equivalent to %q", tc.want)
+			}
+			want, err := gps.NewSemverConstraintIC(tc.want)
+			if err != nil {
+				t.Fatalf("test case has an invalid want constraint %q: %s", tc.want, err)
+			}
+			if got.String() != want.String() {
+				t.Fatalf("widenHistoryAwareConstraint = %s, want %s", got, want)
+			}
+		})
+	}
+}
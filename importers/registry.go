@@ -0,0 +1,99 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/internal/gps"
+)
+
+// Importer converts configuration and lock information from another
+// dependency manager into a dep Manifest and Lock. Implementations
+// typically embed Base to reuse the common resolution logic and register
+// themselves with Register so that `dep init` can find them by name or
+// during auto-detection.
+type Importer interface {
+	// Name returns the identifying name of the other dependency manager,
+	// e.g. "glide" or "godep". It is the value accepted by the
+	// `dep init -importer` flag.
+	Name() string
+
+	// HasDepMetadata reports whether dir contains configuration this
+	// importer knows how to read.
+	HasDepMetadata(dir string) bool
+
+	// Import reads the other tool's configuration from dir and converts
+	// it into a dep Manifest and Lock.
+	Import(dir string) (*dep.Manifest, *dep.Lock, error)
+}
+
+// Factory constructs an Importer that uses logger, verbose and sm the same
+// way Base does.
+type Factory func(logger *log.Logger, verbose bool, sm gps.SourceManager) Importer
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]Factory)
+)
+
+// Register adds a Factory to the registry under name, making it available
+// to `dep init -importer=name` and to auto-detection via Detect. Register
+// is meant to be called from an init function in the package that
+// implements the importer, the same way database/sql drivers register
+// themselves.
+//
+// Register panics if name is already registered.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := registry[name]; dup {
+		panic("importers: Register called twice for importer " + name)
+	}
+	registry[name] = factory
+}
+
+// Names returns the names of all registered importers, sorted
+// lexicographically so that auto-detection order is deterministic.
+func Names() []string {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New constructs the importer registered under name. It reports false if no
+// importer is registered under that name.
+func New(name string, logger *log.Logger, verbose bool, sm gps.SourceManager) (Importer, bool) {
+	registryMu.Lock()
+	factory, ok := registry[name]
+	registryMu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(logger, verbose, sm), true
+}
+
+// Detect iterates the registered importers in name order and returns the
+// first one whose HasDepMetadata reports true for dir. It returns nil if no
+// registered importer recognizes dir.
+func Detect(dir string, logger *log.Logger, verbose bool, sm gps.SourceManager) Importer {
+	for _, name := range Names() {
+		im, _ := New(name, logger, verbose, sm)
+		if im.HasDepMetadata(dir) {
+			return im
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"log"
+	"testing"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/internal/gps"
+)
+
+// fakeImporter is a minimal Importer for exercising the registry without a
+// real config format or a gps.SourceManager.
+type fakeImporter struct {
+	name        string
+	hasMetadata bool
+}
+
+func (f *fakeImporter) Name() string { return f.name }
+
+func (f *fakeImporter) HasDepMetadata(dir string) bool { return f.hasMetadata }
+func (f *fakeImporter) Import(dir string) (*dep.Manifest, *dep.Lock, error) {
+	return dep.NewManifest(), &dep.Lock{}, nil
+}
+
+func fakeFactory(name string, hasMetadata bool) Factory {
+	return func(logger *log.Logger, verbose bool, sm gps.SourceManager) Importer {
+		return &fakeImporter{name: name, hasMetadata: hasMetadata}
+	}
+}
+
+func TestRegisterAndNew(t *testing.T) {
+	const name = "registry-test-new"
+	Register(name, fakeFactory(name, false))
+
+	im, ok := New(name, nil, false, nil)
+	if !ok {
+		t.Fatalf("New(%q) reported not registered, want registered", name)
+	}
+	if im.Name() != name {
+		t.Fatalf("New(%q).Name() = %q, want %q", name, im.Name(), name)
+	}
+
+	if _, ok := New("registry-test-never-registered", nil, false, nil); ok {
+		t.Fatal("New reported an unregistered name as registered")
+	}
+}
+
+func TestRegisterPanicsOnDuplicateName(t *testing.T) {
+	const name = "registry-test-dup"
+	Register(name, fakeFactory(name, false))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Register did not panic on a duplicate name")
+		}
+	}()
+	Register(name, fakeFactory(name, false))
+}
+
+// TestDetectReturnsFirstMatchInNameOrder registers importers so that the one
+// earliest in lexicographic name order both has metadata and isn't the one
+// registered first, proving Detect iterates in Names() order rather than
+// registration order, and that it skips importers whose HasDepMetadata is
+// false along the way.
+func TestDetectReturnsFirstMatchInNameOrder(t *testing.T) {
+	Register("registry-test-detect-c", fakeFactory("registry-test-detect-c", true))
+	Register("registry-test-detect-a", fakeFactory("registry-test-detect-a", false))
+	Register("registry-test-detect-b", fakeFactory("registry-test-detect-b", true))
+
+	im := Detect("unused-dir", nil, false, nil)
+	if im == nil {
+		t.Fatal("Detect found no importer, want registry-test-detect-b")
+	}
+	if im.Name() != "registry-test-detect-b" {
+		t.Fatalf("Detect returned %q, want registry-test-detect-b (first match in name order, after skipping the metadata-less registry-test-detect-a)", im.Name())
+	}
+}
+
+func TestNamesIsSorted(t *testing.T) {
+	Register("registry-test-names-z", fakeFactory("registry-test-names-z", false))
+	Register("registry-test-names-a", fakeFactory("registry-test-names-a", false))
+
+	names := Names()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("Names() is not sorted: %v", names)
+		}
+	}
+}
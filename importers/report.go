@@ -0,0 +1,61 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import "github.com/golang/dep/internal/gps"
+
+// ResolutionKind describes how ImportPackages determined a project's
+// locked version.
+type ResolutionKind string
+
+const (
+	// ResolutionNone means the project had no lock hint to resolve.
+	ResolutionNone ResolutionKind = "none"
+	// ResolutionTag means the lock hint matched a tag or semver version
+	// directly.
+	ResolutionTag ResolutionKind = "tag"
+	// ResolutionRevision means the lock hint was a revision that matched
+	// one or more known versions (tags or branches) with that revision.
+	ResolutionRevision ResolutionKind = "revision"
+	// ResolutionBranchConstraint means the lock hint was an untagged
+	// revision, resolved using a branch constraint from the manifest.
+	ResolutionBranchConstraint ResolutionKind = "branch-constraint"
+	// ResolutionFallbackRevision means no tag, version, or branch
+	// constraint applied, so the project was locked to the bare
+	// revision.
+	ResolutionFallbackRevision ResolutionKind = "fallback-revision"
+)
+
+// ImportReport is the audit trail produced by a single ImportPackages or
+// ImportPackagesWithOptions call: for every imported project, the raw
+// config values that were read and how dep resolved them into a
+// constraint and locked version.
+type ImportReport struct {
+	// Tool is the name of the dependency manager the projects were
+	// imported from, e.g. "glide".
+	Tool string `json:"tool"`
+
+	Projects []ImportRecord `json:"projects"`
+}
+
+// ImportRecord is the provenance of a single imported project.
+type ImportRecord struct {
+	ProjectRoot gps.ProjectRoot `json:"projectRoot"`
+	Source      string          `json:"source,omitempty"`
+
+	// ConstraintHint and LockHint are the raw values read from the other
+	// tool's config, before dep interpreted them.
+	ConstraintHint string `json:"constraintHint,omitempty"`
+	LockHint       string `json:"lockHint,omitempty"`
+
+	// ConfigFile and ConfigLine locate where the hints were read from, when
+	// the importer could attribute them to a specific line.
+	ConfigFile string `json:"configFile,omitempty"`
+	ConfigLine int    `json:"configLine,omitempty"`
+
+	// Resolution is the path ImportPackages took to turn LockHint into a
+	// locked version.
+	Resolution ResolutionKind `json:"resolution"`
+}
@@ -0,0 +1,91 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/dep/internal/gps"
+)
+
+// TestImportReportRoundTripsThroughJSON guards the field names and values
+// writeImportReport's consumers (dep init -import-report) depend on: tool,
+// per-project hints, resolution kind, and config file/line.
+func TestImportReportRoundTripsThroughJSON(t *testing.T) {
+	report := &ImportReport{
+		Tool: "godep",
+		Projects: []ImportRecord{
+			{
+				ProjectRoot:    gps.ProjectRoot("github.com/example/foo"),
+				Source:         "github.com/fork/foo",
+				ConstraintHint: "^1.2.3",
+				LockHint:       "deadbeef",
+				ConfigFile:     "Godeps/Godeps.json",
+				ConfigLine:     7,
+				Resolution:     ResolutionTag,
+			},
+			{
+				ProjectRoot: gps.ProjectRoot("github.com/example/bar"),
+				Resolution:  ResolutionNone,
+			},
+		},
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal: %s", err)
+	}
+
+	var got ImportReport
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+
+	if got.Tool != report.Tool {
+		t.Errorf("Tool = %q, want %q", got.Tool, report.Tool)
+	}
+	if len(got.Projects) != len(report.Projects) {
+		t.Fatalf("Projects = %d entries, want %d", len(got.Projects), len(report.Projects))
+	}
+
+	want := report.Projects[0]
+	gotProj := got.Projects[0]
+	if gotProj.ProjectRoot != want.ProjectRoot {
+		t.Errorf("Projects[0].ProjectRoot = %q, want %q", gotProj.ProjectRoot, want.ProjectRoot)
+	}
+	if gotProj.Source != want.Source {
+		t.Errorf("Projects[0].Source = %q, want %q", gotProj.Source, want.Source)
+	}
+	if gotProj.ConstraintHint != want.ConstraintHint {
+		t.Errorf("Projects[0].ConstraintHint = %q, want %q", gotProj.ConstraintHint, want.ConstraintHint)
+	}
+	if gotProj.LockHint != want.LockHint {
+		t.Errorf("Projects[0].LockHint = %q, want %q", gotProj.LockHint, want.LockHint)
+	}
+	if gotProj.ConfigFile != want.ConfigFile {
+		t.Errorf("Projects[0].ConfigFile = %q, want %q", gotProj.ConfigFile, want.ConfigFile)
+	}
+	if gotProj.ConfigLine != want.ConfigLine {
+		t.Errorf("Projects[0].ConfigLine = %d, want %d", gotProj.ConfigLine, want.ConfigLine)
+	}
+	if gotProj.Resolution != want.Resolution {
+		t.Errorf("Projects[0].Resolution = %q, want %q", gotProj.Resolution, want.Resolution)
+	}
+
+	// Omitempty fields should be absent from the wire format when unset,
+	// so a minimal project record doesn't carry a page of empty strings.
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal into raw map: %s", err)
+	}
+	projects := raw["projects"].([]interface{})
+	minimal := projects[1].(map[string]interface{})
+	for _, field := range []string{"source", "constraintHint", "lockHint", "configFile", "configLine"} {
+		if _, ok := minimal[field]; ok {
+			t.Errorf("minimal project record unexpectedly has field %q: %v", field, minimal)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/internal/gps"
+	"github.com/golang/dep/internal/gps/pkgtree"
+	"github.com/pkg/errors"
+)
+
+// ImportStrategy selects what ImportPackages does when an imported
+// constraint hint conflicts with the imported lock hint for the same
+// project.
+type ImportStrategy int
+
+const (
+	// PreferLock keeps the locked revision and drops the conflicting
+	// constraint, falling back to *. This is dep's historical behavior,
+	// and the zero value of ImportStrategy.
+	PreferLock ImportStrategy = iota
+
+	// PreferConstraint keeps the imported constraint and drops the locked
+	// revision, leaving the next solve to pick a version that satisfies
+	// the constraint.
+	PreferConstraint
+
+	// Reconcile runs gps.Solver with the imported manifest as hard
+	// constraints and the imported lock as a preferred solution,
+	// accepting whichever version the solver backtracks to for the
+	// conflicting project instead of dropping either side.
+	Reconcile
+)
+
+// ConflictError reports that an imported constraint hint could not be
+// reconciled with its project's imported lock hint.
+type ConflictError struct {
+	ProjectRoot gps.ProjectRoot
+	Hint        string
+	LockHint    string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: imported constraint %q conflicts with locked revision %q", e.ProjectRoot, e.Hint, e.LockHint)
+}
+
+// ConflictErrors collects the ConflictErrors produced by a single
+// ImportPackagesWithOptions call run with the Reconcile strategy.
+type ConflictErrors []*ConflictError
+
+func (e ConflictErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, c := range e {
+		msgs[i] = c.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// reconcileRootImportPath is the synthetic import path used as the root of
+// the package tree fed to gps.Solver by reconcile. It only needs to import
+// the conflicted project so the solver actually pulls it into the solve;
+// it is never written to disk or shown to the user.
+const reconcileRootImportPath = "github.com/golang/dep/importers/reconcile-root"
+
+// reconcileParams builds the gps.SolveParameters that pull ident into a
+// solve as a hard dependency constrained by c, biased by locked as a
+// preferred version. It layers onto (without mutating) the manifest and
+// lock accumulated so far by ImportPackages, so that already-resolved
+// projects still constrain the solve. Split out from reconcile so it can be
+// tested without invoking gps.Solver.
+func (i *Base) reconcileParams(ident gps.ProjectIdentifier, c gps.Constraint, locked gps.Version) gps.SolveParameters {
+	manifest := dep.NewManifest()
+	for pr, pp := range i.manifest.Constraints {
+		manifest.Constraints[pr] = pp
+	}
+	// The constraint being reconciled is a hard requirement of the solve,
+	// not merely a preference, so it must land in the manifest alongside
+	// (and override) anything already recorded for this project.
+	manifest.Constraints[ident.ProjectRoot] = gps.ProjectProperties{
+		Source:     ident.Source,
+		Constraint: c,
+	}
+
+	lock := &dep.Lock{P: append([]gps.LockedProject{}, i.lock.P...)}
+	if locked != nil {
+		// The conflicting locked version is still a useful bias: it tells
+		// the solver which nearby version to prefer if more than one
+		// satisfies c.
+		lock.P = append(lock.P, gps.NewLockedProject(ident, locked, nil))
+	}
+
+	// A package tree with a single synthetic root that imports
+	// ident.ProjectRoot is what actually pulls ident into the solve;
+	// without it the solver has nothing requiring ident's presence at all.
+	tree := pkgtree.PackageTree{
+		ImportRoot: reconcileRootImportPath,
+		Packages: map[string]pkgtree.PackageOrErr{
+			reconcileRootImportPath: {
+				P: pkgtree.Package{
+					ImportPath: reconcileRootImportPath,
+					Imports:    []string{string(ident.ProjectRoot)},
+				},
+			},
+		},
+	}
+
+	return gps.SolveParameters{
+		RootDir:         ".",
+		RootPackageTree: tree,
+		Manifest:        manifest,
+		Lock:            lock,
+		ProjectAnalyzer: i.analyzer,
+	}
+}
+
+// reconcile asks gps.Solver for a version of ident that satisfies c, with
+// the manifest and lock accumulated so far as the solve's other inputs. It
+// returns an error if no such version exists.
+func (i *Base) reconcile(ident gps.ProjectIdentifier, c gps.Constraint, locked gps.Version) (gps.Version, error) {
+	params := i.reconcileParams(ident, c, locked)
+
+	solver, err := gps.Prepare(params, i.sm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not prepare solver to reconcile %s", ident.ProjectRoot)
+	}
+
+	soln, err := solver.Solve(context.TODO())
+	if err != nil {
+		return nil, errors.Wrapf(err, "no solution reconciles %s with constraint %v", ident.ProjectRoot, c)
+	}
+
+	for _, lp := range soln.Projects() {
+		if lp.Ident().ProjectRoot == ident.ProjectRoot {
+			return lp.Version(), nil
+		}
+	}
+
+	return nil, errors.Errorf("solver's solution does not include %s", ident.ProjectRoot)
+}
@@ -0,0 +1,73 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package importers
+
+import (
+	"log"
+	"testing"
+
+	"github.com/golang/dep"
+	"github.com/golang/dep/internal/gps"
+)
+
+// TestReconcileParamsConstrainsConflictedProject guards against the
+// Reconcile strategy silently running an import-agnostic solve: the
+// constraint being reconciled must land in the manifest passed to
+// gps.Solver, and the conflicted project must actually be reachable from
+// the root package tree, or the solver has nothing forcing it into the
+// solution at all.
+func TestReconcileParamsConstrainsConflictedProject(t *testing.T) {
+	b := NewBase(log.New(nil, "", 0), false, nil, nil, "test")
+	b.manifest.Constraints["github.com/other/project"] = gps.ProjectProperties{
+		Constraint: gps.Any(),
+	}
+
+	ident := gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot("github.com/example/conflicted")}
+	constraint := gps.NewBranch("v2")
+	locked := constraint.Pair(gps.Revision("deadbeef"))
+
+	params := b.reconcileParams(ident, constraint, locked)
+
+	got, ok := params.Manifest.(*dep.Manifest).Constraints[ident.ProjectRoot]
+	if !ok {
+		t.Fatalf("reconcileParams did not add a manifest constraint for %s", ident.ProjectRoot)
+	}
+	if got.Constraint != constraint {
+		t.Fatalf("reconcileParams.Manifest constraint = %v, want %v", got.Constraint, constraint)
+	}
+
+	// The pre-existing constraint for other projects must survive
+	// untouched, since reconcile should layer onto the accumulated
+	// manifest rather than replacing it.
+	if _, ok := params.Manifest.(*dep.Manifest).Constraints["github.com/other/project"]; !ok {
+		t.Fatal("reconcileParams dropped an unrelated pre-existing manifest constraint")
+	}
+
+	tree := params.RootPackageTree
+	root, ok := tree.Packages[tree.ImportRoot]
+	if !ok {
+		t.Fatalf("reconcileParams root package tree has no package at its own ImportRoot %q", tree.ImportRoot)
+	}
+
+	found := false
+	for _, imp := range root.P.Imports {
+		if imp == string(ident.ProjectRoot) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("reconcileParams root package does not import %s, so the solver would never pull it in", ident.ProjectRoot)
+	}
+
+	foundLock := false
+	for _, lp := range params.Lock.(*dep.Lock).P {
+		if lp.Ident().ProjectRoot == ident.ProjectRoot {
+			foundLock = true
+		}
+	}
+	if !foundLock {
+		t.Fatalf("reconcileParams did not bias the solve with the conflicting locked version for %s", ident.ProjectRoot)
+	}
+}